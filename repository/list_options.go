@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"fmt"
+
+	"testcontainers-demo/models"
+)
+
+// SortColumn is the set of columns List results may be ordered by. It is
+// validated against an allowlist before ever reaching a query, so it can
+// never be used to inject arbitrary SQL via ORDER BY.
+type SortColumn string
+
+const (
+	SortByID        SortColumn = "id"
+	SortByCreatedAt SortColumn = "created_at"
+	SortByEmail     SortColumn = "email"
+	SortByName      SortColumn = "name"
+)
+
+// sortColumnAllowlist maps each supported SortColumn to the literal SQL
+// identifier it may expand to.
+var sortColumnAllowlist = map[SortColumn]string{
+	SortByID:        "id",
+	SortByCreatedAt: "created_at",
+	SortByEmail:     "email",
+	SortByName:      "name",
+}
+
+// SortOrder is the direction results are returned in.
+type SortOrder string
+
+const (
+	OrderAsc  SortOrder = "asc"
+	OrderDesc SortOrder = "desc"
+)
+
+// ListOptions controls pagination, sorting, and filtering for
+// ListWithOptions. The zero value lists the first page of users ordered by
+// ID ascending.
+type ListOptions struct {
+	// Limit caps the number of users returned. Values <= 0 default to 50.
+	Limit int
+
+	// AfterID is a keyset cursor: results start strictly after the row with
+	// this ID in the requested sort order. Zero means "from the start".
+	AfterID int
+
+	// SortBy selects the ORDER BY column. Defaults to SortByID.
+	SortBy SortColumn
+
+	// Order selects ascending or descending order. Defaults to OrderAsc.
+	Order SortOrder
+
+	// NamePattern, if set, filters to users whose name matches this
+	// ILIKE pattern (e.g. "%smith%"). Subsumes FindByNamePattern.
+	NamePattern string
+
+	// EmailPattern, if set, filters to users whose email matches this
+	// ILIKE pattern.
+	EmailPattern string
+}
+
+// ListPage is one page of a ListWithOptions result.
+type ListPage struct {
+	Users      []models.User
+	NextCursor *int
+	HasMore    bool
+}
+
+const defaultListLimit = 50
+
+// resolve fills in defaults and validates SortBy, returning the SQL column
+// name to order by and its SQL direction.
+func (o ListOptions) resolve() (limit int, column, direction string, err error) {
+	limit = o.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	sortBy := o.SortBy
+	if sortBy == "" {
+		sortBy = SortByID
+	}
+	column, ok := sortColumnAllowlist[sortBy]
+	if !ok {
+		return 0, "", "", fmt.Errorf("invalid sort column: %q", o.SortBy)
+	}
+
+	switch o.Order {
+	case "", OrderAsc:
+		direction = "ASC"
+	case OrderDesc:
+		direction = "DESC"
+	default:
+		return 0, "", "", fmt.Errorf("invalid sort order: %q", o.Order)
+	}
+
+	return limit, column, direction, nil
+}
@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"testcontainers-demo/models"
+)
+
+// MemUserRepo is an in-memory UserRepo backed by a map, intended for unit
+// tests that want to exercise repository-consuming code without paying for
+// a real database. It is safe for concurrent use.
+type MemUserRepo struct {
+	mu     sync.Mutex
+	users  map[int]*models.User
+	nextID int
+}
+
+// NewMemUserRepo creates an empty in-memory user repository.
+func NewMemUserRepo() *MemUserRepo {
+	return &MemUserRepo{
+		users: make(map[int]*models.User),
+	}
+}
+
+var _ UserRepo = (*MemUserRepo)(nil)
+
+// GetByID retrieves a user by their ID
+func (r *MemUserRepo) GetByID(id int) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	copied := *user
+	return &copied, nil
+}
+
+// GetByEmail retrieves a user by their email
+func (r *MemUserRepo) GetByEmail(email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+
+	return nil, fmt.Errorf("user not found")
+}
+
+// Create inserts a new user
+func (r *MemUserRepo) Create(email, name string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return nil, fmt.Errorf("failed to create user: duplicate email")
+		}
+	}
+
+	r.nextID++
+	user := &models.User{
+		ID:        r.nextID,
+		Email:     email,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	r.users[user.ID] = user
+
+	copied := *user
+	return &copied, nil
+}
+
+// Update modifies an existing user
+func (r *MemUserRepo) Update(id int, email, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return fmt.Errorf("user not found")
+	}
+
+	for otherID, other := range r.users {
+		if otherID != id && other.Email == email {
+			return fmt.Errorf("failed to update user: duplicate email")
+		}
+	}
+
+	user.Email = email
+	user.Name = name
+
+	return nil
+}
+
+// Delete removes a user
+func (r *MemUserRepo) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return fmt.Errorf("user not found")
+	}
+
+	delete(r.users, id)
+	return nil
+}
+
+// List retrieves all users, ordered by ID to mirror the SQL repository.
+func (r *MemUserRepo) List() ([]models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.sortedUsers(func(*models.User) bool { return true }), nil
+}
+
+// FindByNamePattern finds users whose name matches a pattern. Leading and
+// trailing '%' wildcards are stripped and the rest is matched as a
+// case-insensitive substring, so "Smith%" behaves like "%Smith%"; '_' and
+// inner '%' are matched literally.
+func (r *MemUserRepo) FindByNamePattern(pattern string) ([]models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	needle := strings.ToLower(strings.Trim(pattern, "%"))
+
+	return r.sortedUsers(func(u *models.User) bool {
+		return strings.Contains(strings.ToLower(u.Name), needle)
+	}), nil
+}
+
+// CountUsers returns total number of users
+func (r *MemUserRepo) CountUsers() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.users), nil
+}
+
+// GetRecentUsers returns users created in the last N days
+func (r *MemUserRepo) GetRecentUsers(days int) ([]models.User, error) {
+	return r.GetUsersCreatedSince(time.Duration(days) * 24 * time.Hour)
+}
+
+// GetUsersCreatedBetween returns users created within [from, to], ordered
+// by created_at descending.
+func (r *MemUserRepo) GetUsersCreatedBetween(from, to time.Time) ([]models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := r.sortedUsers(func(u *models.User) bool {
+		return !u.CreatedAt.Before(from) && !u.CreatedAt.After(to)
+	})
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.After(users[j].CreatedAt)
+	})
+
+	return users, nil
+}
+
+// GetUsersCreatedSince returns users created within the last d, ordered by
+// created_at descending.
+func (r *MemUserRepo) GetUsersCreatedSince(d time.Duration) ([]models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := time.Now().Add(-d)
+
+	users := r.sortedUsers(func(u *models.User) bool {
+		return !u.CreatedAt.Before(cutoff)
+	})
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.After(users[j].CreatedAt)
+	})
+
+	return users, nil
+}
+
+// ListWithOptions returns a page of users according to opts, mirroring
+// UserRepository.ListWithOptions's keyset-over-(sort column, id) semantics
+// so both backends paginate identically.
+func (r *MemUserRepo) ListWithOptions(ctx context.Context, opts ListOptions) (*ListPage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limit, column, direction, err := opts.resolve()
+	if err != nil {
+		return nil, err
+	}
+	ascending := direction == "ASC"
+
+	var cursorValue any
+	if opts.AfterID != 0 {
+		cursorUser, ok := r.users[opts.AfterID]
+		if !ok {
+			return nil, fmt.Errorf("cursor user not found")
+		}
+		cursorValue = sortKey(cursorUser, column)
+	}
+
+	namePattern := strings.ToLower(strings.Trim(opts.NamePattern, "%"))
+	emailPattern := strings.ToLower(strings.Trim(opts.EmailPattern, "%"))
+
+	var candidates []models.User
+	for _, u := range r.users {
+		if namePattern != "" && !strings.Contains(strings.ToLower(u.Name), namePattern) {
+			continue
+		}
+		if emailPattern != "" && !strings.Contains(strings.ToLower(u.Email), emailPattern) {
+			continue
+		}
+		if opts.AfterID != 0 {
+			cmp := compareKeys(sortKey(u, column), cursorValue)
+			if cmp == 0 {
+				cmp = u.ID - opts.AfterID
+			}
+			if (ascending && cmp <= 0) || (!ascending && cmp >= 0) {
+				continue
+			}
+		}
+		candidates = append(candidates, *u)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		cmp := compareKeys(sortKey(&candidates[i], column), sortKey(&candidates[j], column))
+		if cmp == 0 {
+			cmp = candidates[i].ID - candidates[j].ID
+		}
+		if ascending {
+			return cmp < 0
+		}
+		return cmp > 0
+	})
+
+	page := &ListPage{Users: candidates}
+	if len(candidates) > limit {
+		page.Users = candidates[:limit]
+		page.HasMore = true
+		nextCursor := page.Users[limit-1].ID
+		page.NextCursor = &nextCursor
+	}
+
+	return page, nil
+}
+
+// sortKey extracts the value of the given allowlisted column from u, for
+// use by ListWithOptions's in-memory ordering and keyset comparisons.
+func sortKey(u *models.User, column string) any {
+	switch column {
+	case "id":
+		return u.ID
+	case "created_at":
+		return u.CreatedAt
+	case "email":
+		return u.Email
+	case "name":
+		return u.Name
+	default:
+		return nil
+	}
+}
+
+// compareKeys returns -1, 0, or 1 as a compares less than, equal to, or
+// greater than b. Both values must have been produced by sortKey for the
+// same column.
+func compareKeys(a, b any) int {
+	switch av := a.(type) {
+	case int:
+		bv := b.(int)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		default:
+			return 0
+		}
+	case time.Time:
+		bv := b.(time.Time)
+		switch {
+		case av.Before(bv):
+			return -1
+		case av.After(bv):
+			return 1
+		default:
+			return 0
+		}
+	case string:
+		bv := b.(string)
+		return strings.Compare(av, bv)
+	default:
+		return 0
+	}
+}
+
+// sortedUsers returns a copy of every user matching keep, ordered by ID.
+// Callers must hold r.mu.
+func (r *MemUserRepo) sortedUsers(keep func(*models.User) bool) []models.User {
+	var users []models.User
+	for _, user := range r.users {
+		if keep(user) {
+			users = append(users, *user)
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	return users
+}
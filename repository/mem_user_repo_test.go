@@ -0,0 +1,11 @@
+package repository
+
+import "testing"
+
+// TestMemUserRepo runs the shared UserRepo contract against the in-memory
+// backend. Unlike the Postgres-backed suite in user_repository_test.go,
+// this needs no TEST_DSN/USE_CONTAINER and no Docker, so it runs in
+// milliseconds as part of every `go test`.
+func TestMemUserRepo(t *testing.T) {
+	runUserRepoContract(t, NewMemUserRepo())
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"testcontainers-demo/models"
+)
+
+// runUserRepoContract exercises the UserRepo contract against repo. It is
+// shared between the in-memory and Postgres-backed suites so both
+// implementations are held to the same behavior.
+func runUserRepoContract(t *testing.T, repo UserRepo) {
+	t.Helper()
+
+	t.Run("Create and GetByID", func(t *testing.T) {
+		user, err := repo.Create("contract-create@example.com", "Contract Create")
+		if err != nil {
+			t.Fatalf("Create() returned error: %v", err)
+		}
+		defer repo.Delete(user.ID)
+
+		got, err := repo.GetByID(user.ID)
+		if err != nil {
+			t.Fatalf("GetByID() returned error: %v", err)
+		}
+		if got.Email != user.Email || got.Name != user.Name {
+			t.Errorf("GetByID() = %+v, want %+v", got, user)
+		}
+	})
+
+	t.Run("Create Duplicate Email", func(t *testing.T) {
+		user, err := repo.Create("contract-dup@example.com", "Contract Dup")
+		if err != nil {
+			t.Fatalf("Create() returned error: %v", err)
+		}
+		defer repo.Delete(user.ID)
+
+		if _, err := repo.Create("contract-dup@example.com", "Someone Else"); err == nil {
+			t.Error("Create() with duplicate email expected error, got nil")
+		}
+	})
+
+	t.Run("GetByEmail", func(t *testing.T) {
+		user, err := repo.Create("contract-email@example.com", "Contract Email")
+		if err != nil {
+			t.Fatalf("Create() returned error: %v", err)
+		}
+		defer repo.Delete(user.ID)
+
+		got, err := repo.GetByEmail("contract-email@example.com")
+		if err != nil {
+			t.Fatalf("GetByEmail() returned error: %v", err)
+		}
+		if got.ID != user.ID {
+			t.Errorf("GetByEmail() = %+v, want ID %d", got, user.ID)
+		}
+
+		if _, err := repo.GetByEmail("contract-missing@example.com"); err == nil {
+			t.Error("GetByEmail() for missing email expected error, got nil")
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		user, err := repo.Create("contract-update@example.com", "Before Update")
+		if err != nil {
+			t.Fatalf("Create() returned error: %v", err)
+		}
+		defer repo.Delete(user.ID)
+
+		if err := repo.Update(user.ID, "contract-updated@example.com", "After Update"); err != nil {
+			t.Fatalf("Update() returned error: %v", err)
+		}
+
+		got, err := repo.GetByID(user.ID)
+		if err != nil {
+			t.Fatalf("GetByID() returned error: %v", err)
+		}
+		if got.Email != "contract-updated@example.com" || got.Name != "After Update" {
+			t.Errorf("GetByID() after Update() = %+v", got)
+		}
+
+		if err := repo.Update(9999999, "nobody@example.com", "Nobody"); err == nil {
+			t.Error("Update() for missing user expected error, got nil")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		user, err := repo.Create("contract-delete@example.com", "Contract Delete")
+		if err != nil {
+			t.Fatalf("Create() returned error: %v", err)
+		}
+
+		if err := repo.Delete(user.ID); err != nil {
+			t.Fatalf("Delete() returned error: %v", err)
+		}
+
+		if _, err := repo.GetByID(user.ID); err == nil {
+			t.Error("GetByID() after Delete() expected error, got nil")
+		}
+
+		if err := repo.Delete(9999999); err == nil {
+			t.Error("Delete() for missing user expected error, got nil")
+		}
+	})
+
+	t.Run("List and FindByNamePattern", func(t *testing.T) {
+		user, err := repo.Create("contract-list@example.com", "Contract Pattern")
+		if err != nil {
+			t.Fatalf("Create() returned error: %v", err)
+		}
+		defer repo.Delete(user.ID)
+
+		users, err := repo.List()
+		if err != nil {
+			t.Fatalf("List() returned error: %v", err)
+		}
+
+		found := false
+		for _, u := range users {
+			if u.Email == user.Email {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("List() did not include created user")
+		}
+
+		matches, err := repo.FindByNamePattern("%Pattern%")
+		if err != nil {
+			t.Fatalf("FindByNamePattern() returned error: %v", err)
+		}
+		found = false
+		for _, u := range matches {
+			if u.Email == user.Email {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("FindByNamePattern() did not find the created user")
+		}
+	})
+
+	t.Run("CountUsers and GetRecentUsers", func(t *testing.T) {
+		before, err := repo.CountUsers()
+		if err != nil {
+			t.Fatalf("CountUsers() returned error: %v", err)
+		}
+
+		user, err := repo.Create("contract-recent@example.com", "Contract Recent")
+		if err != nil {
+			t.Fatalf("Create() returned error: %v", err)
+		}
+		defer repo.Delete(user.ID)
+
+		after, err := repo.CountUsers()
+		if err != nil {
+			t.Fatalf("CountUsers() returned error: %v", err)
+		}
+		if after != before+1 {
+			t.Errorf("CountUsers() = %d, want %d", after, before+1)
+		}
+
+		recent, err := repo.GetRecentUsers(1)
+		if err != nil {
+			t.Fatalf("GetRecentUsers() returned error: %v", err)
+		}
+		found := false
+		for _, u := range recent {
+			if u.Email == user.Email {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("GetRecentUsers() did not include the just-created user")
+		}
+	})
+
+	t.Run("GetUsersCreatedSince and GetUsersCreatedBetween", func(t *testing.T) {
+		user, err := repo.Create("contract-since@example.com", "Contract Since")
+		if err != nil {
+			t.Fatalf("Create() returned error: %v", err)
+		}
+		defer repo.Delete(user.ID)
+
+		since, err := repo.GetUsersCreatedSince(time.Hour)
+		if err != nil {
+			t.Fatalf("GetUsersCreatedSince() returned error: %v", err)
+		}
+		if !containsEmail(since, user.Email) {
+			t.Error("GetUsersCreatedSince() did not include the just-created user")
+		}
+
+		if _, err := repo.GetUsersCreatedSince(-time.Hour); err != nil {
+			t.Errorf("GetUsersCreatedSince() with negative duration returned error: %v", err)
+		}
+
+		between, err := repo.GetUsersCreatedBetween(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("GetUsersCreatedBetween() returned error: %v", err)
+		}
+		if !containsEmail(between, user.Email) {
+			t.Error("GetUsersCreatedBetween() did not include the just-created user")
+		}
+	})
+
+	t.Run("ListWithOptions", func(t *testing.T) {
+		user, err := repo.Create("contract-page@example.com", "Contract Page")
+		if err != nil {
+			t.Fatalf("Create() returned error: %v", err)
+		}
+		defer repo.Delete(user.ID)
+
+		// A second user guarantees a Limit: 1 first page has more to follow,
+		// even on a backend that starts empty.
+		second, err := repo.Create("contract-page-2@example.com", "Contract Page 2")
+		if err != nil {
+			t.Fatalf("Create() returned error: %v", err)
+		}
+		defer repo.Delete(second.ID)
+
+		page, err := repo.ListWithOptions(context.Background(), ListOptions{Limit: 1000})
+		if err != nil {
+			t.Fatalf("ListWithOptions() returned error: %v", err)
+		}
+		if !containsEmail(page.Users, user.Email) {
+			t.Error("ListWithOptions() did not include the just-created user")
+		}
+
+		if _, err := repo.ListWithOptions(context.Background(), ListOptions{SortBy: "not_a_column"}); err == nil {
+			t.Error("ListWithOptions() with invalid SortBy expected error, got nil")
+		}
+
+		firstPage, err := repo.ListWithOptions(context.Background(), ListOptions{Limit: 1, SortBy: SortByID, Order: OrderAsc})
+		if err != nil {
+			t.Fatalf("ListWithOptions() first page returned error: %v", err)
+		}
+		if len(firstPage.Users) != 1 || firstPage.NextCursor == nil {
+			t.Fatalf("expected first page of 1 user with a cursor, got %+v", firstPage)
+		}
+
+		secondPage, err := repo.ListWithOptions(context.Background(), ListOptions{Limit: 1000, AfterID: *firstPage.NextCursor, SortBy: SortByID, Order: OrderAsc})
+		if err != nil {
+			t.Fatalf("ListWithOptions() second page returned error: %v", err)
+		}
+		for _, u := range secondPage.Users {
+			if u.ID == firstPage.Users[0].ID {
+				t.Errorf("second page re-returned user %d from first page", u.ID)
+			}
+		}
+	})
+}
+
+func containsEmail(users []models.User, email string) bool {
+	for _, u := range users {
+		if u.Email == email {
+			return true
+		}
+	}
+	return false
+}
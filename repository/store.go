@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Store owns the database connection pool and hands out transaction-scoped
+// repositories, so multi-step operations (e.g. "create user + insert audit
+// row") can be committed or rolled back atomically.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a Store backed by db.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// inTxKey marks a context as already running inside a WithTx callback, so
+// genuine re-entry on the same call chain can be rejected without limiting
+// how many transactions the Store runs concurrently.
+type inTxKey struct{}
+
+// WithTx runs fn inside a database transaction: it begins the transaction,
+// passes fn the transaction's context and a UserRepository bound to it, and
+// commits if fn returns nil or rolls back otherwise (including on panic,
+// which is re-panicked after the rollback). A Store may run any number of
+// transactions concurrently; WithTx only fails fast when fn calls it again
+// using the context it was given, i.e. true nesting on the same call chain.
+func (s *Store) WithTx(ctx context.Context, fn func(ctx context.Context, repo *UserRepository) error) (err error) {
+	if ctx.Value(inTxKey{}) != nil {
+		return fmt.Errorf("repository: nested transaction: WithTx called again from within an existing WithTx callback")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	txCtx := context.WithValue(ctx, inTxKey{}, true)
+	if err := fn(txCtx, newUserRepository(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback also failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
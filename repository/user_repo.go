@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"testcontainers-demo/models"
+)
+
+// UserRepo is the storage-agnostic contract for user persistence. It is
+// satisfied by the Postgres-backed UserRepository as well as MemUserRepo,
+// so callers and tests can swap a real database for an in-memory stand-in.
+type UserRepo interface {
+	GetByID(id int) (*models.User, error)
+	GetByEmail(email string) (*models.User, error)
+	Create(email, name string) (*models.User, error)
+	Update(id int, email, name string) error
+	Delete(id int) error
+	List() ([]models.User, error)
+	FindByNamePattern(pattern string) ([]models.User, error)
+	CountUsers() (int, error)
+	GetRecentUsers(days int) ([]models.User, error)
+	GetUsersCreatedBetween(from, to time.Time) ([]models.User, error)
+	GetUsersCreatedSince(d time.Duration) ([]models.User, error)
+	ListWithOptions(ctx context.Context, opts ListOptions) (*ListPage, error)
+}
+
+var _ UserRepo = (*UserRepository)(nil)
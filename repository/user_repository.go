@@ -1,27 +1,88 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
+
 	"testcontainers-demo/models"
 )
 
+// Querier is the subset of *sql.DB used by UserRepository. Both *sql.DB and
+// *sql.Tx satisfy it, so a UserRepository can run against the pool directly
+// or against a single transaction interchangeably.
+type Querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+var (
+	_ Querier = (*sql.DB)(nil)
+	_ Querier = (*sql.Tx)(nil)
+)
+
 // UserRepository handles database operations for users
 type UserRepository struct {
-	db *sql.DB
+	db      Querier
+	timeout time.Duration
 }
 
-// NewUserRepository creates a new user repository
-func NewUserRepository(db *sql.DB) *UserRepository {
-	return &UserRepository{db: db}
+// Option configures a UserRepository
+type Option func(*UserRepository)
+
+// WithTimeout bounds every query issued through the repository's
+// non-context-aware methods (and the context passed to its Context
+// methods) to at most d, via context.WithTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(r *UserRepository) {
+		r.timeout = d
+	}
+}
+
+// NewUserRepository creates a new user repository backed by db.
+func NewUserRepository(db *sql.DB, opts ...Option) *UserRepository {
+	return newUserRepository(db, opts...)
+}
+
+// newUserRepository builds a repository against any Querier, so WithTx can
+// construct one bound to a transaction without exposing that constructor
+// publicly.
+func newUserRepository(q Querier, opts ...Option) *UserRepository {
+	r := &UserRepository{db: q}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// withTimeout returns a derived context bounded by r.timeout, and the
+// associated cancel func. If no timeout is configured, ctx is returned
+// unchanged along with a no-op cancel func.
+func (r *UserRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.timeout)
 }
 
 // GetByID retrieves a user by their ID
 func (r *UserRepository) GetByID(id int) (*models.User, error) {
+	return r.GetByIDContext(context.Background(), id)
+}
+
+// GetByIDContext retrieves a user by their ID, aborting if ctx is cancelled
+// or the repository's configured timeout elapses.
+func (r *UserRepository) GetByIDContext(ctx context.Context, id int) (*models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT id, email, name, created_at FROM users WHERE id = $1"
 
 	var user models.User
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Name,
@@ -40,10 +101,19 @@ func (r *UserRepository) GetByID(id int) (*models.User, error) {
 
 // GetByEmail retrieves a user by their email
 func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+	return r.GetByEmailContext(context.Background(), email)
+}
+
+// GetByEmailContext retrieves a user by their email, aborting if ctx is
+// cancelled or the repository's configured timeout elapses.
+func (r *UserRepository) GetByEmailContext(ctx context.Context, email string) (*models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT id, email, name, created_at FROM users WHERE email = $1"
 
 	var user models.User
-	err := r.db.QueryRow(query, email).Scan(
+	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Name,
@@ -62,6 +132,15 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 
 // Create inserts a new user
 func (r *UserRepository) Create(email, name string) (*models.User, error) {
+	return r.CreateContext(context.Background(), email, name)
+}
+
+// CreateContext inserts a new user, aborting if ctx is cancelled or the
+// repository's configured timeout elapses.
+func (r *UserRepository) CreateContext(ctx context.Context, email, name string) (*models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := `
 		INSERT INTO users (email, name)
 		VALUES ($1, $2)
@@ -69,7 +148,7 @@ func (r *UserRepository) Create(email, name string) (*models.User, error) {
 	`
 
 	var user models.User
-	err := r.db.QueryRow(query, email, name).Scan(
+	err := r.db.QueryRowContext(ctx, query, email, name).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Name,
@@ -85,9 +164,18 @@ func (r *UserRepository) Create(email, name string) (*models.User, error) {
 
 // Update modifies an existing user
 func (r *UserRepository) Update(id int, email, name string) error {
+	return r.UpdateContext(context.Background(), id, email, name)
+}
+
+// UpdateContext modifies an existing user, aborting if ctx is cancelled or
+// the repository's configured timeout elapses.
+func (r *UserRepository) UpdateContext(ctx context.Context, id int, email, name string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := "UPDATE users SET email = $1, name = $2 WHERE id = $3"
 
-	result, err := r.db.Exec(query, email, name, id)
+	result, err := r.db.ExecContext(ctx, query, email, name, id)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
@@ -106,9 +194,18 @@ func (r *UserRepository) Update(id int, email, name string) error {
 
 // Delete removes a user
 func (r *UserRepository) Delete(id int) error {
+	return r.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext removes a user, aborting if ctx is cancelled or the
+// repository's configured timeout elapses.
+func (r *UserRepository) DeleteContext(ctx context.Context, id int) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := "DELETE FROM users WHERE id = $1"
 
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -127,9 +224,18 @@ func (r *UserRepository) Delete(id int) error {
 
 // List retrieves all users
 func (r *UserRepository) List() ([]models.User, error) {
+	return r.ListContext(context.Background())
+}
+
+// ListContext retrieves all users, aborting if ctx is cancelled or the
+// repository's configured timeout elapses.
+func (r *UserRepository) ListContext(ctx context.Context) ([]models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT id, email, name, created_at FROM users ORDER BY id"
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
@@ -154,9 +260,19 @@ func (r *UserRepository) List() ([]models.User, error) {
 
 // FindByNamePattern finds users whose name matches a pattern
 func (r *UserRepository) FindByNamePattern(pattern string) ([]models.User, error) {
+	return r.FindByNamePatternContext(context.Background(), pattern)
+}
+
+// FindByNamePatternContext finds users whose name matches a pattern,
+// aborting if ctx is cancelled or the repository's configured timeout
+// elapses.
+func (r *UserRepository) FindByNamePatternContext(ctx context.Context, pattern string) ([]models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT id, email, name, created_at FROM users WHERE name ILIKE $1 ORDER BY id"
 
-	rows, err := r.db.Query(query, pattern)
+	rows, err := r.db.QueryContext(ctx, query, pattern)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find users by pattern: %w", err)
 	}
@@ -181,10 +297,19 @@ func (r *UserRepository) FindByNamePattern(pattern string) ([]models.User, error
 
 // CountUsers returns total number of users
 func (r *UserRepository) CountUsers() (int, error) {
+	return r.CountUsersContext(context.Background())
+}
+
+// CountUsersContext returns the total number of users, aborting if ctx is
+// cancelled or the repository's configured timeout elapses.
+func (r *UserRepository) CountUsersContext(ctx context.Context) (int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT COUNT(*) FROM users"
 
 	var count int
-	err := r.db.QueryRow(query).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count users: %w", err)
 	}
@@ -192,18 +317,148 @@ func (r *UserRepository) CountUsers() (int, error) {
 	return count, nil
 }
 
+// ListWithOptions returns a page of users according to opts: limited,
+// sorted by an allowlisted column, optionally filtered by name/email
+// pattern, and paginated via a keyset cursor over (sort column, id) rather
+// than OFFSET so pages stay stable as rows are inserted or deleted.
+func (r *UserRepository) ListWithOptions(ctx context.Context, opts ListOptions) (*ListPage, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	limit, column, direction, err := opts.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	cursorOp := ">"
+	if direction == "DESC" {
+		cursorOp = "<"
+	}
+
+	var (
+		conditions []string
+		args       []any
+	)
+
+	if opts.AfterID != 0 {
+		query := fmt.Sprintf("SELECT %s FROM users WHERE id = $1", column)
+		row := r.db.QueryRowContext(ctx, query, opts.AfterID)
+
+		// Scan into a column-appropriate type rather than *any: scanning a
+		// text column into *any can hand the driver's raw []byte back to
+		// database/sql, which then binds it as bytea and Postgres rejects
+		// comparing that against the text column below.
+		var cursorValue any
+		var err error
+		switch column {
+		case "created_at":
+			var v time.Time
+			err = row.Scan(&v)
+			cursorValue = v
+		case "email", "name":
+			var v string
+			err = row.Scan(&v)
+			cursorValue = v
+		default:
+			var v int
+			err = row.Scan(&v)
+			cursorValue = v
+		}
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("cursor user not found")
+			}
+			return nil, fmt.Errorf("failed to resolve cursor: %w", err)
+		}
+
+		args = append(args, cursorValue, opts.AfterID)
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, cursorOp, len(args)-1, len(args)))
+	}
+
+	if opts.NamePattern != "" {
+		args = append(args, opts.NamePattern)
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+
+	if opts.EmailPattern != "" {
+		args = append(args, opts.EmailPattern)
+		conditions = append(conditions, fmt.Sprintf("email ILIKE $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, limit+1)
+	query := fmt.Sprintf(
+		"SELECT id, email, name, created_at FROM users %s ORDER BY %s %s, id %s LIMIT $%d",
+		where, column, direction, direction, len(args),
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.Name, &user.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	page := &ListPage{Users: users}
+	if len(users) > limit {
+		page.Users = users[:limit]
+		page.HasMore = true
+		nextCursor := page.Users[limit-1].ID
+		page.NextCursor = &nextCursor
+	}
+
+	return page, nil
+}
+
 // GetRecentUsers returns users created in the last N days
 func (r *UserRepository) GetRecentUsers(days int) ([]models.User, error) {
+	return r.GetRecentUsersContext(context.Background(), days)
+}
+
+// GetRecentUsersContext returns users created in the last N days, aborting
+// if ctx is cancelled or the repository's configured timeout elapses.
+func (r *UserRepository) GetRecentUsersContext(ctx context.Context, days int) ([]models.User, error) {
+	return r.GetUsersCreatedSinceContext(ctx, time.Duration(days)*24*time.Hour)
+}
+
+// GetUsersCreatedBetween returns users created within [from, to], ordered
+// by created_at descending.
+func (r *UserRepository) GetUsersCreatedBetween(from, to time.Time) ([]models.User, error) {
+	return r.GetUsersCreatedBetweenContext(context.Background(), from, to)
+}
+
+// GetUsersCreatedBetweenContext returns users created within [from, to],
+// aborting if ctx is cancelled or the repository's configured timeout
+// elapses.
+func (r *UserRepository) GetUsersCreatedBetweenContext(ctx context.Context, from, to time.Time) ([]models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := `
-		SELECT id, email, name, created_at 
-		FROM users 
-		WHERE created_at >= NOW() - INTERVAL '%d days'
+		SELECT id, email, name, created_at
+		FROM users
+		WHERE created_at >= $1 AND created_at <= $2
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(fmt.Sprintf(query, days))
+	rows, err := r.db.QueryContext(ctx, query, from, to)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recent users: %w", err)
+		return nil, fmt.Errorf("failed to get users created between: %w", err)
 	}
 	defer rows.Close()
 
@@ -222,4 +477,48 @@ func (r *UserRepository) GetRecentUsers(days int) ([]models.User, error) {
 	}
 
 	return users, nil
-}
\ No newline at end of file
+}
+
+// GetUsersCreatedSince returns users created within the last d, ordered by
+// created_at descending.
+func (r *UserRepository) GetUsersCreatedSince(d time.Duration) ([]models.User, error) {
+	return r.GetUsersCreatedSinceContext(context.Background(), d)
+}
+
+// GetUsersCreatedSinceContext returns users created within the last d,
+// aborting if ctx is cancelled or the repository's configured timeout
+// elapses. The cutoff is computed by Postgres, parameterized via $1, so no
+// user-controlled value is ever interpolated into the query text.
+func (r *UserRepository) GetUsersCreatedSinceContext(ctx context.Context, d time.Duration) ([]models.User, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT id, email, name, created_at
+		FROM users
+		WHERE created_at >= NOW() - ($1 * INTERVAL '1 second')
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, d.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users created since: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(&user.ID, &user.Email, &user.Name, &user.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
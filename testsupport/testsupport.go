@@ -0,0 +1,131 @@
+// Package testsupport provides a shared, isolated Postgres test harness for
+// testcontainers-demo. A single container is started lazily for the whole
+// test binary; each call to NewIsolatedDB then gets its own schema cloned
+// from the seeded public.users table, so tests can run with t.Parallel()
+// and assert exact row counts instead of "at least N".
+package testsupport
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+var (
+	setupOnce   sync.Once
+	setupErr    error
+	baseConnStr string
+)
+
+// ensureContainer starts the package's single Postgres container the first
+// time it's needed. Later calls reuse it; testcontainers-go's Ryuk reaper
+// cleans it up once the test binary exits.
+func ensureContainer() error {
+	setupOnce.Do(func() {
+		ctx := context.Background()
+
+		container, err := postgres.RunContainer(ctx,
+			testcontainers.WithImage("postgres:15-alpine"),
+			postgres.WithDatabase("testdb"),
+			postgres.WithUsername("testuser"),
+			postgres.WithPassword("testpass"),
+			postgres.WithInitScripts("../migrations/init.sql"),
+			testcontainers.WithWaitStrategy(
+				wait.ForLog("database system is ready to accept connections").
+					WithOccurrence(2).
+					WithStartupTimeout(5*time.Second)),
+		)
+		if err != nil {
+			setupErr = fmt.Errorf("testsupport: failed to start postgres container: %w", err)
+			return
+		}
+
+		connStr, err := container.ConnectionString(ctx, "sslmode=disable")
+		if err != nil {
+			setupErr = fmt.Errorf("testsupport: failed to get connection string: %w", err)
+			return
+		}
+		baseConnStr = connStr
+	})
+
+	return setupErr
+}
+
+// NewIsolatedDB returns a *sql.DB connected to a freshly created schema
+// seeded with a copy of the public.users table (structure and rows). The
+// returned connection pins every session to that schema via search_path, so
+// callers can treat it exactly like a dedicated database. The schema and
+// connection are dropped/closed automatically via t.Cleanup.
+func NewIsolatedDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	if err := ensureContainer(); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	admin, err := sql.Open("postgres", baseConnStr)
+	if err != nil {
+		t.Fatalf("testsupport: failed to open admin connection: %v", err)
+	}
+	defer admin.Close()
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "_")
+	ctx := context.Background()
+
+	if _, err := admin.ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA %s", schema)); err != nil {
+		t.Fatalf("testsupport: failed to create schema %s: %v", schema, err)
+	}
+	if _, err := admin.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s.users (LIKE public.users INCLUDING ALL)", schema)); err != nil {
+		t.Fatalf("testsupport: failed to clone users table into %s: %v", schema, err)
+	}
+	if _, err := admin.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s.users SELECT * FROM public.users", schema)); err != nil {
+		t.Fatalf("testsupport: failed to seed %s.users: %v", schema, err)
+	}
+
+	db, err := sql.Open("postgres", scopedConnStr(schema))
+	if err != nil {
+		t.Fatalf("testsupport: failed to open scoped connection for %s: %v", schema, err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatalf("testsupport: failed to ping scoped connection for %s: %v", schema, err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+
+		cleanup, err := sql.Open("postgres", baseConnStr)
+		if err != nil {
+			t.Logf("testsupport: failed to open cleanup connection for %s: %v", schema, err)
+			return
+		}
+		defer cleanup.Close()
+
+		if _, err := cleanup.ExecContext(context.Background(), fmt.Sprintf("DROP SCHEMA %s CASCADE", schema)); err != nil {
+			t.Logf("testsupport: failed to drop schema %s: %v", schema, err)
+		}
+	})
+
+	return db
+}
+
+// scopedConnStr appends a search_path runtime parameter to the container's
+// base connection string, so every connection drawn from the returned pool
+// defaults to schema without any per-query SET search_path.
+func scopedConnStr(schema string) string {
+	sep := "&"
+	if !strings.Contains(baseConnStr, "?") {
+		sep = "?"
+	}
+	return baseConnStr + sep + "options=" + url.QueryEscape(fmt.Sprintf("-c search_path=%s", schema))
+}